@@ -26,6 +26,8 @@ const (
 )
 
 // ResourceGraphDefinitionSpec defines the desired state of ResourceGraphDefinition
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.schema) || self.schema.scope != 'Cluster' || !has(self.resources) || self.resources.all(r, !has(r.externalRef) || (has(r.externalRef.metadata.namespace) && r.externalRef.metadata.namespace != ''))",message="externalRef.metadata.namespace is required on every resource when schema.scope is Cluster, since there is no instance namespace to default to"
 type ResourceGraphDefinitionSpec struct {
 	// The schema of the resourcegraphdefinition, which includes the
 	// apiVersion, kind, spec, status, types, and some validation
@@ -48,6 +50,9 @@ type ResourceGraphDefinitionSpec struct {
 
 // Schema represents the attributes that define an instance of
 // a resourcegraphdefinition.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.versions) || size(self.versions) == 0 || size(self.versions.filter(v, v.storage)) == 1",message="exactly one version must set storage=true when versions is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.versions) || size(self.versions) <= 1 || has(self.conversion)",message="conversion is required when more than one version is declared"
 type Schema struct {
 	// The kind of the resourcegraphdefinition. This is used to generate
 	// and create the CRD for the resourcegraphdefinition.
@@ -69,6 +74,27 @@ type Schema struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default="kro.run"
 	Group string `json:"group,omitempty"`
+	// Scope selects whether the generated CRD is Namespaced or Cluster
+	// scoped. If omitted, it defaults to "Namespaced".
+	//
+	// Apart from the XValidation rule on ResourceGraphDefinitionSpec, which
+	// requires every externalRef to set a namespace when Scope is Cluster,
+	// nothing in this repository reads Scope: it does not change which
+	// namespaces instances are watched in, and DefaultServiceAccounts are
+	// not resolved per instance namespace.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Namespaced;Cluster
+	// +kubebuilder:default="Namespaced"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="scope is immutable"
+	Scope extv1.ResourceScope `json:"scope,omitempty"`
+	// Names holds overrides for the naming conventions of the generated CRD.
+	//
+	// Nothing in this repository reads Names: the overrides are stored on
+	// the object and are not applied to any CRD.
+	//
+	// +kubebuilder:validation:Optional
+	Names *Names `json:"names,omitempty"`
 	// The spec of the resourcegraphdefinition. Typically, this is the spec of
 	// the CRD that the resourcegraphdefinition is managing. This is adhering
 	// to the SimpleSchema spec
@@ -94,6 +120,162 @@ type Schema struct {
 	//
 	// +kubebuilder:validation:Optional
 	AdditionalPrinterColumns []extv1.CustomResourceColumnDefinition `json:"additionalPrinterColumns,omitempty"`
+
+	// Subresources configures the scale and status subresources exposed on
+	// the generated CRD.
+	//
+	// +kubebuilder:validation:Optional
+	Subresources *Subresources `json:"subresources,omitempty"`
+
+	// SelectableFields declares additional fields that can be used with
+	// field selectors against instances of the generated CRD.
+	//
+	// +kubebuilder:validation:Optional
+	SelectableFields []SelectableField `json:"selectableFields,omitempty"`
+
+	// Versions allows declaring multiple served CRD versions for this
+	// resourcegraphdefinition, each with its own SimpleSchema spec/status.
+	// When set, it takes precedence over the top-level APIVersion/Spec/Status/
+	// Types/Validation/AdditionalPrinterColumns fields, which are kept as a
+	// shorthand for the common single-version case.
+	//
+	// +kubebuilder:validation:Optional
+	Versions []SchemaVersion `json:"versions,omitempty"`
+
+	// Conversion defines how objects are converted between the versions
+	// declared in Versions. It is required as soon as more than one version
+	// is declared.
+	//
+	// No conversion webhook server or CEL evaluator is part of this
+	// repository, so no conversion webhook is registered on the generated
+	// CRD and these rules are not evaluated.
+	//
+	// +kubebuilder:validation:Optional
+	Conversion *Conversion `json:"conversion,omitempty"`
+}
+
+// SchemaVersion represents a single served version of a resourcegraphdefinition's
+// generated CRD, mirroring the fields available in the single-version shorthand.
+//
+// +kubebuilder:validation:XValidation:rule="!(has(self.lifecycle) && self.lifecycle.stage == 'Sunset' && has(self.storage) && self.storage)",message="a Sunset version cannot be the storage version"
+type SchemaVersion struct {
+	// The APIVersion served by this version. This is used to generate
+	// and create the CRD for the resourcegraphdefinition.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^v[0-9]+(alpha[0-9]+|beta[0-9]+)?$`
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Served indicates whether this version is served by the apiserver.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	Served bool `json:"served,omitempty"`
+	// Storage indicates whether this version is the storage version.
+	// Exactly one version must have Storage set to true.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Storage bool `json:"storage,omitempty"`
+	// The spec of the resourcegraphdefinition for this version, adhering
+	// to the SimpleSchema spec.
+	Spec runtime.RawExtension `json:"spec,omitempty"`
+	// Types is a map of custom type definitions scoped to this version.
+	// Each type definition is also adhering to the SimpleSchema spec.
+	Types runtime.RawExtension `json:"types,omitempty"`
+	// The status of the resourcegraphdefinition for this version, adhering
+	// to the SimpleSchema spec.
+	Status runtime.RawExtension `json:"status,omitempty"`
+	// Validation is a list of validation rules that are applied to this
+	// version.
+	Validation []Validation `json:"validation,omitempty"`
+	// AdditionalPrinterColumns defines additional printer columns for this
+	// version that will be passed down to the created CRD.
+	//
+	// +kubebuilder:validation:Optional
+	AdditionalPrinterColumns []extv1.CustomResourceColumnDefinition `json:"additionalPrinterColumns,omitempty"`
+
+	// Lifecycle describes where this version sits in its deprecation
+	// lifecycle. When omitted, the version is treated as Stable.
+	//
+	// +kubebuilder:validation:Optional
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// SchemaLifecycleStage represents the lifecycle stage of a SchemaVersion.
+type SchemaLifecycleStage string
+
+const (
+	SchemaLifecycleStageAlpha      SchemaLifecycleStage = "Alpha"
+	SchemaLifecycleStageBeta       SchemaLifecycleStage = "Beta"
+	SchemaLifecycleStageStable     SchemaLifecycleStage = "Stable"
+	SchemaLifecycleStageDeprecated SchemaLifecycleStage = "Deprecated"
+	SchemaLifecycleStageSunset     SchemaLifecycleStage = "Sunset"
+)
+
+// Lifecycle captures the deprecation state of a SchemaVersion. A Sunset
+// version cannot be the storage version, which is enforced by an
+// XValidation rule on SchemaVersion at admission time.
+//
+// Nothing in this repository reads Lifecycle otherwise: no CRD version is
+// marked deprecated from it, instance creation against a Sunset version is
+// not rejected, and no SchemaDeprecated status condition is set.
+type Lifecycle struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Alpha;Beta;Stable;Deprecated;Sunset
+	Stage SchemaLifecycleStage `json:"stage,omitempty"`
+	// DeprecationWarning holds the warning text for a Deprecated version.
+	// It is not copied into the generated CRD version's deprecationWarning
+	// (see Lifecycle).
+	//
+	// +kubebuilder:validation:Optional
+	DeprecationWarning string `json:"deprecationWarning,omitempty"`
+	// SunsetDate is the date after which this version is expected to be
+	// removed.
+	//
+	// +kubebuilder:validation:Optional
+	SunsetDate metav1.Time `json:"sunsetDate,omitempty"`
+	// ReplacementVersion points callers at the version that should be used
+	// instead of this one.
+	//
+	// +kubebuilder:validation:Optional
+	ReplacementVersion string `json:"replacementVersion,omitempty"`
+}
+
+// Conversion holds the rules for converting a resourcegraphdefinition's
+// objects between its declared versions. It only carries configuration:
+// nothing in this repository registers a conversion webhook on the
+// generated CRD, answers a ConversionReview, or evaluates these rules.
+type Conversion struct {
+	// Rules is the set of per version-pair conversion rules.
+	//
+	// +kubebuilder:validation:Required
+	Rules []ConversionRule `json:"rules,omitempty"`
+}
+
+// ConversionRule describes how to convert an object from one version to
+// another using CEL expressions evaluated against the source object.
+// Fields not covered by FieldMappings map to the same path on the
+// destination object. Nothing in this repository evaluates the rule
+// (see Conversion).
+type ConversionRule struct {
+	// +kubebuilder:validation:Required
+	FromVersion string `json:"fromVersion,omitempty"`
+	// +kubebuilder:validation:Required
+	ToVersion string `json:"toVersion,omitempty"`
+	// FieldMappings lists the destination paths that require an explicit
+	// CEL expression to compute their value during conversion.
+	//
+	// +kubebuilder:validation:Optional
+	FieldMappings []FieldMapping `json:"fieldMappings,omitempty"`
+}
+
+// FieldMapping maps a CEL expression, evaluated against the source object,
+// to a JSON path on the destination object.
+type FieldMapping struct {
+	// +kubebuilder:validation:Required
+	ToPath string `json:"toPath,omitempty"`
+	// +kubebuilder:validation:Required
+	Expression string `json:"expression,omitempty"`
 }
 
 type Validation struct {
@@ -101,10 +283,71 @@ type Validation struct {
 	Message    string `json:"message,omitempty"`
 }
 
+// Subresources configures the subresources of the generated CRD, mirroring
+// apiextensions/v1.CustomResourceSubresources.
+type Subresources struct {
+	// Scale, when set, exposes a /scale subresource on instances of the
+	// generated CRD so they can be driven by HPA or `kubectl scale`.
+	//
+	// +kubebuilder:validation:Optional
+	Scale *ScaleSubresource `json:"scale,omitempty"`
+	// Status, when true, exposes a /status subresource on instances of the
+	// generated CRD. Defaults to true.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	Status bool `json:"status,omitempty"`
+}
+
+// ScaleSubresource mirrors apiextensions/v1.CustomResourceSubresourceScale.
+//
+// SpecReplicasPath, StatusReplicasPath and LabelSelectorPath are not checked
+// at admission against the fields declared in Schema.Spec or Schema.Status:
+// a path that names no declared field is accepted.
+type ScaleSubresource struct {
+	// +kubebuilder:validation:Required
+	SpecReplicasPath string `json:"specReplicasPath,omitempty"`
+	// +kubebuilder:validation:Required
+	StatusReplicasPath string `json:"statusReplicasPath,omitempty"`
+	// +kubebuilder:validation:Optional
+	LabelSelectorPath *string `json:"labelSelectorPath,omitempty"`
+}
+
+// SelectableField declares a field that can be used as a field selector
+// against instances of the generated CRD. JSONPath is not checked at
+// admission against the fields declared in the resourcegraphdefinition's
+// spec or status: a path that names no declared field is accepted.
+type SelectableField struct {
+	// +kubebuilder:validation:Required
+	JSONPath string `json:"jsonPath,omitempty"`
+}
+
+// Names holds overrides for the naming conventions used by the generated
+// CRD (see Schema.Names), mirroring apiextensions/v1.CustomResourceDefinitionNames.
+type Names struct {
+	// +kubebuilder:validation:Optional
+	Plural string `json:"plural,omitempty"`
+	// +kubebuilder:validation:Optional
+	Singular string `json:"singular,omitempty"`
+	// +kubebuilder:validation:Optional
+	ListKind string `json:"listKind,omitempty"`
+	// +kubebuilder:validation:Optional
+	ShortNames []string `json:"shortNames,omitempty"`
+	// +kubebuilder:validation:Optional
+	Categories []string `json:"categories,omitempty"`
+}
+
 type ExternalRefMetadata struct {
 	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty"`
-	// +kubebuilder:validation:Required
+	// Namespace of the referenced resource. For a Cluster-scoped
+	// resourcegraphdefinition it must be set to a non-empty value; this is
+	// enforced by an XValidation rule on ResourceGraphDefinitionSpec. For a
+	// Namespaced resourcegraphdefinition it may be omitted, and nothing in
+	// this repository fills in the instance's namespace: the field stays
+	// empty.
+	//
+	// +kubebuilder:validation:Optional
 	Namespace string `json:"namespace,omitempty"`
 }
 
@@ -118,8 +361,31 @@ type ExternalRef struct {
 	Kind string `json:"kind"`
 	// +kubebuilder:validation:Required
 	Metadata ExternalRefMetadata `json:"metadata"`
+	// ReadPolicy selects when the external resource's observed values are
+	// (re-)read. Snapshot resolves the resource once and pins its values for
+	// use in CEL expressions; Live re-reads it on every reconcile. Defaults
+	// to Live.
+	//
+	// Nothing in this repository reads ReadPolicy, so setting Snapshot does
+	// not pin any values.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Snapshot;Live
+	// +kubebuilder:default="Live"
+	ReadPolicy ReadPolicy `json:"readPolicy,omitempty"`
 }
 
+// ReadPolicy determines when an ExternalRef's observed values are re-read.
+type ReadPolicy string
+
+const (
+	// ReadPolicySnapshot resolves the external resource once, on first
+	// successful read, and reuses those values on subsequent reconciles.
+	ReadPolicySnapshot ReadPolicy = "Snapshot"
+	// ReadPolicyLive re-reads the external resource on every reconcile.
+	ReadPolicyLive ReadPolicy = "Live"
+)
+
 // +kubebuilder:validation:XValidation:rule="(has(self.template) && !has(self.externalRef)) || (!has(self.template) && has(self.externalRef))",message="exactly one of template or externalRef must be provided"
 type Resource struct {
 	// +kubebuilder:validation:Required
@@ -132,8 +398,39 @@ type Resource struct {
 	ReadyWhen []string `json:"readyWhen,omitempty"`
 	// +kubebuilder:validation:Optional
 	IncludeWhen []string `json:"includeWhen,omitempty"`
+	// ConflictPolicy selects how a resource that has drifted from its
+	// template or externalRef out of band is reconciled. Defaults to
+	// Overwrite.
+	//
+	// Nothing in this repository reads ConflictPolicy or IgnorePaths.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Overwrite;ServerSideApplyWithOwnership;IgnorePaths
+	// +kubebuilder:default="Overwrite"
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+	// IgnorePaths lists JSON paths excluded from drift diffing when
+	// ConflictPolicy is IgnorePaths.
+	//
+	// +kubebuilder:validation:Optional
+	IgnorePaths []string `json:"ignorePaths,omitempty"`
 }
 
+// ConflictPolicy determines how the reconciler reconciles a resource whose
+// live state has drifted from the graph's desired state.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyOverwrite always reapplies the full desired state,
+	// discarding out-of-band changes.
+	ConflictPolicyOverwrite ConflictPolicy = "Overwrite"
+	// ConflictPolicyServerSideApplyWithOwnership reconciles via server-side
+	// apply, only taking ownership of the fields the graph manages.
+	ConflictPolicyServerSideApplyWithOwnership ConflictPolicy = "ServerSideApplyWithOwnership"
+	// ConflictPolicyIgnorePaths reapplies the desired state while excluding
+	// the fields listed in Resource.IgnorePaths from the diff.
+	ConflictPolicyIgnorePaths ConflictPolicy = "IgnorePaths"
+)
+
 // ResourceGraphDefinitionState defines the state of the resource graph definition.
 type ResourceGraphDefinitionState string
 
@@ -150,7 +447,9 @@ type ResourceGraphDefinitionStatus struct {
 	State ResourceGraphDefinitionState `json:"state,omitempty"`
 	// TopologicalOrder is the topological order of the resourcegraphdefinition graph
 	TopologicalOrder []string `json:"topologicalOrder,omitempty"`
-	// Conditions represent the latest available observations of an object's state
+	// Conditions represent the latest available observations of an object's state.
+	// No condition is set here for Versions that carry a Deprecated or Sunset
+	// lifecycle stage; see Lifecycle.
 	Conditions Conditions `json:"conditions,omitempty"`
 	// Resources represents the resources, and their information (dependencies for now)
 	Resources []ResourceInformation `json:"resources,omitempty"`
@@ -0,0 +1,68 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import "testing"
+
+// TestEnumValuesAreDistinct guards against a typo'd constant silently
+// colliding with another value of the same enum, which +kubebuilder:validation:Enum
+// markers would not catch since they list the values as literal strings,
+// independently of these constants.
+func TestEnumValuesAreDistinct(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []string
+	}{
+		{
+			name: "ConflictPolicy",
+			values: []string{
+				string(ConflictPolicyOverwrite),
+				string(ConflictPolicyServerSideApplyWithOwnership),
+				string(ConflictPolicyIgnorePaths),
+			},
+		},
+		{
+			name: "ReadPolicy",
+			values: []string{
+				string(ReadPolicySnapshot),
+				string(ReadPolicyLive),
+			},
+		},
+		{
+			name: "SchemaLifecycleStage",
+			values: []string{
+				string(SchemaLifecycleStageAlpha),
+				string(SchemaLifecycleStageBeta),
+				string(SchemaLifecycleStageStable),
+				string(SchemaLifecycleStageDeprecated),
+				string(SchemaLifecycleStageSunset),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seen := make(map[string]bool, len(tc.values))
+			for _, v := range tc.values {
+				if v == "" {
+					t.Errorf("%s: empty enum value", tc.name)
+				}
+				if seen[v] {
+					t.Errorf("%s: duplicate enum value %q", tc.name, v)
+				}
+				seen[v] = true
+			}
+		})
+	}
+}